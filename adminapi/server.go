@@ -0,0 +1,126 @@
+// Package adminapi exposes health, readiness, status, and remote-control
+// endpoints for a running anserem process, so it can be health-checked and
+// operated the way Docker/Kubernetes and ISP webhooks expect.
+package adminapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Config configures the admin HTTP server.
+type Config struct {
+	// Addr is the bind address, e.g. ":8081".
+	Addr string
+	// AuthToken, if set, is required as "Bearer <token>" on every request.
+	AuthToken string
+	// TLSCertFile/TLSKeyFile, if both set, serve the admin API over HTTPS.
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ForcedRefreshInterval mirrors Options.forcedRefreshInterval: /readyz
+	// fails once the last refresh is older than 2x this interval.
+	ForcedRefreshInterval time.Duration
+
+	// Tracker supplies the current status.
+	Tracker *Tracker
+	// Refresh is invoked by POST /refresh to force an immediate tick.
+	Refresh func(ctx context.Context)
+}
+
+// Server is the embedded admin HTTP server.
+type Server struct {
+	cfg    Config
+	server *http.Server
+}
+
+// New builds and starts a Server listening on cfg.Addr in the background.
+func New(cfg Config) *Server {
+	s := &Server{cfg: cfg}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.HandleFunc("/status", s.handleStatus)
+	mux.HandleFunc("/refresh", s.handleRefresh)
+
+	s.server = &http.Server{Addr: cfg.Addr, Handler: s.authenticate(mux)}
+
+	go func() {
+		var err error
+		if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+			err = s.server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("adminapi: server stopped: %v", err)
+		}
+	}()
+
+	return s
+}
+
+// Close shuts the admin server down.
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.cfg.AuthToken == "" {
+		return next
+	}
+	want := "Bearer " + s.cfg.AuthToken
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != want {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	lastRefresh := s.cfg.Tracker.LastRefresh()
+	ready := !lastRefresh.IsZero() &&
+		time.Since(lastRefresh) < 2*s.cfg.ForcedRefreshInterval &&
+		s.cfg.Tracker.AnyProviderHealthy()
+
+	if !ready {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.cfg.Tracker.Status()); err != nil {
+		log.Printf("adminapi: error writing status response: %v", err)
+	}
+}
+
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	if s.cfg.Refresh == nil {
+		http.Error(w, "refresh not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	s.cfg.Refresh(r.Context())
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,92 @@
+package adminapi
+
+import (
+	"sync"
+	"time"
+)
+
+// ProviderResult is the outcome of the most recent update attempt for a
+// single provider.
+type ProviderResult struct {
+	OK    bool      `json:"ok"`
+	Error string    `json:"error,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Status is a point-in-time snapshot of the refresh loop, served as JSON by
+// GET /status.
+type Status struct {
+	IP          string                    `json:"ip"`
+	LastRefresh time.Time                 `json:"last_refresh"`
+	Providers   map[string]ProviderResult `json:"providers"`
+}
+
+// Tracker accumulates the state a running Options instance reports after
+// every tick, so the admin server always has something to answer with.
+type Tracker struct {
+	mu          sync.RWMutex
+	ip          string
+	lastRefresh time.Time
+	providers   map[string]ProviderResult
+}
+
+// NewTracker builds an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{providers: make(map[string]ProviderResult)}
+}
+
+// RecordRefresh updates the last detected ip and refresh time.
+func (t *Tracker) RecordRefresh(ip string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ip = ip
+	t.lastRefresh = at
+}
+
+// RecordProvider updates the last result for a single provider.
+func (t *Tracker) RecordProvider(name string, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	result := ProviderResult{OK: err == nil, At: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	t.providers[name] = result
+}
+
+// Status returns a snapshot safe to serialize or inspect.
+func (t *Tracker) Status() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	providers := make(map[string]ProviderResult, len(t.providers))
+	for name, result := range t.providers {
+		providers[name] = result
+	}
+
+	return Status{IP: t.ip, LastRefresh: t.lastRefresh, Providers: providers}
+}
+
+// AnyProviderHealthy reports whether at least one provider's last update
+// succeeded, used by /readyz.
+func (t *Tracker) AnyProviderHealthy() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if len(t.providers) == 0 {
+		return false
+	}
+	for _, result := range t.providers {
+		if result.OK {
+			return true
+		}
+	}
+	return false
+}
+
+// LastRefresh returns the time of the last recorded refresh.
+func (t *Tracker) LastRefresh() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastRefresh
+}
@@ -0,0 +1,102 @@
+// Package anserr provides typed errors for anserem's DDNS and IP-resolution
+// code paths, modeled on the joomcode/errorx notion of an error carrying a
+// small, closed set of "traits" a caller can branch on instead of matching
+// on message strings. The trait here is a retry Category: callers such as
+// the main tick loop use it to decide whether to back off, skip retries
+// entirely, or honor a provider-supplied delay.
+package anserr
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Category classifies an error by how a caller should react to it.
+type Category int
+
+const (
+	// Unknown is the zero value, returned by CategoryOf for errors that were
+	// never classified.
+	Unknown Category = iota
+	// Transient indicates a likely-temporary failure, such as a network
+	// timeout, worth retrying on the next tick without special handling.
+	Transient
+	// Permanent indicates the request itself was rejected and is not
+	// expected to succeed by simply retrying unchanged.
+	Permanent
+	// AuthFailure indicates the configured credentials were rejected.
+	// Retrying with the same credentials will not help.
+	AuthFailure
+	// RateLimited indicates the caller is being throttled and should wait at
+	// least RetryAfter, if set, before trying again.
+	RateLimited
+)
+
+// String returns the lower-case name used for metric labels and log lines.
+func (c Category) String() string {
+	switch c {
+	case Transient:
+		return "transient"
+	case Permanent:
+		return "permanent"
+	case AuthFailure:
+		return "auth_failure"
+	case RateLimited:
+		return "rate_limited"
+	default:
+		return "unknown"
+	}
+}
+
+// Error is a classified error wrapping an underlying cause.
+type Error struct {
+	category   Category
+	cause      error
+	retryAfter time.Duration
+}
+
+// New builds a classified Error with a formatted message.
+func New(category Category, format string, args ...interface{}) *Error {
+	return &Error{category: category, cause: fmt.Errorf(format, args...)}
+}
+
+// Wrap attaches category to an existing error.
+func Wrap(category Category, cause error) *Error {
+	return &Error{category: category, cause: cause}
+}
+
+// RetryAfter decorates e with the duration a caller should wait before
+// retrying, typically used together with RateLimited. It returns e so it can
+// be chained onto New or Wrap.
+func (e *Error) RetryAfter(d time.Duration) *Error {
+	e.retryAfter = d
+	return e
+}
+
+func (e *Error) Error() string { return fmt.Sprintf("%s: %s", e.category, e.cause) }
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// Category returns the error's classification.
+func (e *Error) Category() Category { return e.category }
+
+// CategoryOf walks err's Unwrap chain for an *Error and returns its
+// Category, or Unknown if err was never classified.
+func CategoryOf(err error) Category {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.category
+	}
+	return Unknown
+}
+
+// RetryAfterOf walks err's Unwrap chain for an *Error carrying a RetryAfter
+// duration. ok is false if err was never classified or no duration was set.
+func RetryAfterOf(err error) (d time.Duration, ok bool) {
+	var e *Error
+	if errors.As(err, &e) && e.retryAfter > 0 {
+		return e.retryAfter, true
+	}
+	return 0, false
+}
@@ -0,0 +1,76 @@
+package ipsource
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// dnsLookup resolves the public IP from a resolver that echoes the querying
+// client's own address back, such as OpenDNS's myip.opendns.com or Google's
+// o-o.myaddr.l.google.com.
+type dnsLookup struct {
+	name   string
+	server string // host:port of the authoritative resolver to query directly
+	query  string
+	qtype  uint16
+}
+
+func (s *dnsLookup) Name() string { return "dns:" + s.name }
+
+func (s *dnsLookup) Get(ctx context.Context, family Family) (net.IP, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(s.query), s.qtype)
+
+	client := new(dns.Client)
+	reply, _, err := client.ExchangeContext(ctx, msg, s.server)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("unexpected rcode %s", dns.RcodeToString[reply.Rcode])
+	}
+
+	for _, rr := range reply.Answer {
+		var ip net.IP
+		switch r := rr.(type) {
+		case *dns.A:
+			ip = r.A
+		case *dns.AAAA:
+			ip = r.AAAA
+		case *dns.TXT:
+			if len(r.Txt) > 0 {
+				ip = net.ParseIP(r.Txt[0])
+			}
+		}
+		if ip != nil && family.matches(ip) {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no answer matching requested family")
+}
+
+// NewOpenDNS builds a Source that asks OpenDNS's resolver what address it
+// saw the query arrive from.
+func NewOpenDNS() Source {
+	return &dnsLookup{
+		name:   "opendns",
+		server: "resolver1.opendns.com:53",
+		query:  "myip.opendns.com",
+		qtype:  dns.TypeA,
+	}
+}
+
+// NewGoogleDNS builds a Source that asks Google's public DNS the same way,
+// via its dedicated TXT record.
+func NewGoogleDNS() Source {
+	return &dnsLookup{
+		name:   "google",
+		server: "ns1.google.com:53",
+		query:  "o-o.myaddr.l.google.com",
+		qtype:  dns.TypeTXT,
+	}
+}
@@ -0,0 +1,126 @@
+package ipsource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+)
+
+// httpsJSON resolves the public IP from a JSON HTTP endpoint, using extract
+// to decode the raw response body and pull the address out of it.
+type httpsJSON struct {
+	name    string
+	urlV4   string
+	urlV6   string
+	extract func([]byte) (string, error)
+}
+
+func (s *httpsJSON) Name() string { return "https:" + s.name }
+
+func (s *httpsJSON) Get(ctx context.Context, family Family) (net.IP, error) {
+	url := s.urlV4
+	if family == FamilyV6 {
+		url = s.urlV6
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := s.extract(body)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid ip in response: %q", raw)
+	}
+	if !family.matches(ip) {
+		return nil, fmt.Errorf("resolved ip %s does not match requested family", ip)
+	}
+
+	return ip, nil
+}
+
+type bdcResponse struct {
+	IPString string `json:"ipString"`
+}
+
+func extractBdc(body []byte) (string, error) {
+	var r bdcResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", err
+	}
+	return r.IPString, nil
+}
+
+type ipifyResponse struct {
+	IP string `json:"ip"`
+}
+
+func extractIpify(body []byte) (string, error) {
+	var r ipifyResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", err
+	}
+	return r.IP, nil
+}
+
+type ifconfigCoResponse struct {
+	IP string `json:"ip"`
+}
+
+func extractIfconfigCo(body []byte) (string, error) {
+	var r ifconfigCoResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		return "", err
+	}
+	return r.IP, nil
+}
+
+// NewIpify builds a Source backed by ipify.org.
+func NewIpify() Source {
+	return &httpsJSON{
+		name:    "ipify",
+		urlV4:   "https://api.ipify.org?format=json",
+		urlV6:   "https://api64.ipify.org?format=json",
+		extract: extractIpify,
+	}
+}
+
+// NewIfconfigCo builds a Source backed by ifconfig.co.
+func NewIfconfigCo() Source {
+	return &httpsJSON{
+		name:    "ifconfig.co",
+		urlV4:   "https://ifconfig.co/json",
+		urlV6:   "https://ifconfig.co/json",
+		extract: extractIfconfigCo,
+	}
+}
+
+// NewBdc builds a Source backed by api-bdc.net, the provider anserem used
+// unconditionally before ipsource existed.
+func NewBdc() Source {
+	return &httpsJSON{
+		name:    "api-bdc.net",
+		urlV4:   "https://api-bdc.net/data/client-ip",
+		urlV6:   "https://api-bdc.net/data/client-ip",
+		extract: extractBdc,
+	}
+}
@@ -0,0 +1,56 @@
+package ipsource
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// iface resolves the public IP by scanning local interface addresses,
+// skipping loopback, link-local, and private (RFC 1918 / ULA) ranges
+// instead of relying on a literal string-prefix match.
+type iface struct{}
+
+// NewInterfaceScan builds a Source that looks for a globally routable
+// address already assigned to a local interface.
+func NewInterfaceScan() Source {
+	return &iface{}
+}
+
+func (s *iface) Name() string { return "iface" }
+
+func (s *iface) Get(ctx context.Context, family Family) (net.IP, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		ip, _, err := net.ParseCIDR(addr.String())
+		if err != nil {
+			continue
+		}
+
+		if !family.matches(ip) {
+			continue
+		}
+		if !isGloballyRoutable(ip) {
+			continue
+		}
+
+		return ip, nil
+	}
+
+	return nil, fmt.Errorf("no globally routable address found among %d interface addresses", len(addrs))
+}
+
+// isGloballyRoutable reports whether ip is plausibly a public address,
+// excluding loopback, link-local (unicast and multicast), private, and
+// unspecified ranges.
+func isGloballyRoutable(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() &&
+		!ip.IsUnspecified()
+}
@@ -0,0 +1,30 @@
+package ipsource
+
+import "fmt"
+
+// New builds the Source registered under name. Names matching a STUN/DNS/
+// HTTPS strategy are fixed; "stun" additionally takes the configured server
+// list, falling back to stun.l.google.com:19302 when that list is empty.
+func New(name string, stunServers []string) (Source, error) {
+	switch name {
+	case "ipify":
+		return NewIpify(), nil
+	case "ifconfig.co":
+		return NewIfconfigCo(), nil
+	case "bdc":
+		return NewBdc(), nil
+	case "opendns":
+		return NewOpenDNS(), nil
+	case "google-dns":
+		return NewGoogleDNS(), nil
+	case "iface":
+		return NewInterfaceScan(), nil
+	case "stun":
+		if len(stunServers) == 0 {
+			stunServers = []string{"stun.l.google.com:19302"}
+		}
+		return NewSTUN(stunServers), nil
+	default:
+		return nil, fmt.Errorf("ipsource: unknown source %q", name)
+	}
+}
@@ -0,0 +1,83 @@
+package ipsource
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"anserem/anserr"
+)
+
+// Resolver tries a list of Sources in order, each bounded by its own
+// timeout, and remembers the last Source that succeeded so subsequent calls
+// can skip straight to it until cacheTTL elapses.
+type Resolver struct {
+	sources []Source
+	family  Family
+	timeout time.Duration
+
+	cacheTTL time.Duration
+	mu       sync.Mutex
+	cached   Source
+	cachedAt time.Time
+}
+
+// NewResolver builds a Resolver over sources, tried in the given order.
+// timeout bounds each individual source call; cacheTTL controls how long the
+// last successful source is preferred over re-running the full chain.
+func NewResolver(sources []Source, family Family, timeout, cacheTTL time.Duration) *Resolver {
+	return &Resolver{sources: sources, family: family, timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Resolve returns the current public IP, preferring the last successful
+// source while it remains within cacheTTL and falling back to the full
+// configured chain otherwise.
+func (r *Resolver) Resolve(ctx context.Context) (net.IP, error) {
+	if src := r.cachedSource(); src != nil {
+		if ip, err := r.query(ctx, src); err == nil {
+			return ip, nil
+		}
+		log.Printf("ipsource: cached source %s failed, falling back to full chain", src.Name())
+	}
+
+	var lastErr error
+	for _, src := range r.sources {
+		ip, err := r.query(ctx, src)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		r.mu.Lock()
+		r.cached = src
+		r.cachedAt = time.Now()
+		r.mu.Unlock()
+
+		return ip, nil
+	}
+
+	return nil, anserr.Wrap(anserr.Transient, fmt.Errorf("ipsource: all sources exhausted: %w", lastErr))
+}
+
+func (r *Resolver) query(ctx context.Context, src Source) (net.IP, error) {
+	qCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	ip, err := src.Get(qCtx, r.family)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", src.Name(), err)
+	}
+	return ip, nil
+}
+
+func (r *Resolver) cachedSource() Source {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cached == nil || time.Since(r.cachedAt) > r.cacheTTL {
+		return nil
+	}
+	return r.cached
+}
@@ -0,0 +1,56 @@
+// Package ipsource resolves the machine's public IP address through one of
+// several interchangeable strategies (STUN, HTTPS-JSON APIs, public DNS
+// resolvers, or local interface scanning), trying each in a configured order
+// until one succeeds.
+package ipsource
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// Family selects which IP address family a Source should return.
+type Family int
+
+const (
+	FamilyV4 Family = iota
+	FamilyV6
+	FamilyDual
+)
+
+// ParseFamily parses the --ip-family flag value.
+func ParseFamily(s string) (Family, error) {
+	switch s {
+	case "v4":
+		return FamilyV4, nil
+	case "v6":
+		return FamilyV6, nil
+	case "dual", "":
+		return FamilyDual, nil
+	default:
+		return 0, fmt.Errorf("ipsource: unknown ip family %q (want v4, v6, or dual)", s)
+	}
+}
+
+// matches reports whether ip belongs to the requested family.
+func (f Family) matches(ip net.IP) bool {
+	switch f {
+	case FamilyV4:
+		return ip.To4() != nil
+	case FamilyV6:
+		return ip.To4() == nil && ip.To16() != nil
+	default:
+		return ip != nil
+	}
+}
+
+// Source resolves the public IP address via a single strategy.
+type Source interface {
+	// Name identifies the source in logs, e.g. "https:ipify" or "stun".
+	Name() string
+
+	// Get returns a public IP matching family, or an error if none could be
+	// determined. Implementations must respect ctx's deadline.
+	Get(ctx context.Context, family Family) (net.IP, error)
+}
@@ -0,0 +1,178 @@
+package ipsource
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+const (
+	stunMagicCookie       uint32 = 0x2112A442
+	stunBindingRequest    uint16 = 0x0001
+	stunBindingResponse   uint16 = 0x0101
+	stunAttrXorMappedAddr uint16 = 0x0020
+	stunAttrMappedAddr    uint16 = 0x0001
+)
+
+// stun resolves the public IP by sending an RFC 5389 binding request to one
+// or more STUN servers and reading the mapped address back out of the
+// response.
+type stun struct {
+	servers []string // host:port
+}
+
+// NewSTUN builds a Source that queries servers in order until one answers.
+func NewSTUN(servers []string) Source {
+	return &stun{servers: servers}
+}
+
+func (s *stun) Name() string { return "stun" }
+
+func (s *stun) Get(ctx context.Context, family Family) (net.IP, error) {
+	var lastErr error
+	for _, server := range s.servers {
+		ip, err := s.query(ctx, server)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !family.matches(ip) {
+			lastErr = fmt.Errorf("%s: resolved ip %s does not match requested family", server, ip)
+			continue
+		}
+		return ip, nil
+	}
+	return nil, fmt.Errorf("all stun servers failed, last error: %w", lastErr)
+}
+
+func (s *stun) query(ctx context.Context, server string) (net.IP, error) {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	txID := make([]byte, 12)
+	if _, err := rand.Read(txID); err != nil {
+		return nil, err
+	}
+
+	req := make([]byte, 20)
+	binary.BigEndian.PutUint16(req[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint16(req[2:4], 0) // message length, no attributes
+	binary.BigEndian.PutUint32(req[4:8], stunMagicCookie)
+	copy(req[8:20], txID)
+
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	resp := make([]byte, 512)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseStunResponse(resp[:n], txID)
+}
+
+func parseStunResponse(resp, txID []byte) (net.IP, error) {
+	if len(resp) < 20 {
+		return nil, fmt.Errorf("response too short")
+	}
+	msgType := binary.BigEndian.Uint16(resp[0:2])
+	if msgType != stunBindingResponse {
+		return nil, fmt.Errorf("unexpected message type %#x", msgType)
+	}
+	if binary.BigEndian.Uint32(resp[4:8]) != stunMagicCookie {
+		return nil, fmt.Errorf("bad magic cookie")
+	}
+
+	length := binary.BigEndian.Uint16(resp[2:4])
+	attrs := resp[20:]
+	if int(length) > len(attrs) {
+		return nil, fmt.Errorf("truncated attributes")
+	}
+	attrs = attrs[:length]
+
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLen := binary.BigEndian.Uint16(attrs[2:4])
+		if int(attrLen)+4 > len(attrs) {
+			break
+		}
+		value := attrs[4 : 4+attrLen]
+
+		switch attrType {
+		case stunAttrXorMappedAddr:
+			if ip, err := parseXorMappedAddress(value, txID); err == nil {
+				return ip, nil
+			}
+		case stunAttrMappedAddr:
+			if ip, err := parseMappedAddress(value); err == nil {
+				return ip, nil
+			}
+		}
+
+		// attributes are padded to a 4-byte boundary
+		padded := (int(attrLen) + 3) &^ 3
+		attrs = attrs[4+padded:]
+	}
+
+	return nil, fmt.Errorf("no mapped address attribute in response")
+}
+
+func parseMappedAddress(value []byte) (net.IP, error) {
+	if len(value) < 8 {
+		return nil, fmt.Errorf("mapped address too short")
+	}
+	family := value[1]
+	switch family {
+	case 0x01:
+		return net.IP(value[4:8]), nil
+	case 0x02:
+		if len(value) < 20 {
+			return nil, fmt.Errorf("mapped address v6 too short")
+		}
+		return net.IP(value[4:20]), nil
+	default:
+		return nil, fmt.Errorf("unknown address family %#x", family)
+	}
+}
+
+func parseXorMappedAddress(value, txID []byte) (net.IP, error) {
+	if len(value) < 8 {
+		return nil, fmt.Errorf("xor-mapped address too short")
+	}
+	family := value[1]
+
+	cookie := make([]byte, 4)
+	binary.BigEndian.PutUint32(cookie, stunMagicCookie)
+
+	switch family {
+	case 0x01:
+		xored := make([]byte, 4)
+		for i := range xored {
+			xored[i] = value[4+i] ^ cookie[i]
+		}
+		return net.IP(xored), nil
+	case 0x02:
+		if len(value) < 20 {
+			return nil, fmt.Errorf("xor-mapped address v6 too short")
+		}
+		salt := append(append([]byte{}, cookie...), txID...)
+		xored := make([]byte, 16)
+		for i := range xored {
+			xored[i] = value[4+i] ^ salt[i]
+		}
+		return net.IP(xored), nil
+	default:
+		return nil, fmt.Errorf("unknown address family %#x", family)
+	}
+}
@@ -2,19 +2,24 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
-	influxapi "github.com/influxdata/influxdb-client-go/v2/api"
-	influxwrite "github.com/influxdata/influxdb-client-go/v2/api/write"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	gopsutilnet "github.com/shirou/gopsutil/v3/net"
 	"github.com/urfave/cli/v3"
-	"io"
 	"log"
-	"net/http"
+	"net"
 	"net/mail"
 	"os"
-	"strconv"
 	"time"
+
+	"anserem/adminapi"
+	"anserem/anserr"
+	"anserem/ipsource"
+	"anserem/providers"
+	"anserem/telemetry"
 )
 
 // main is the entry point and starts the application process.
@@ -28,31 +33,47 @@ func main() {
 type Options struct {
 	refreshInterval       time.Duration
 	forcedRefreshInterval time.Duration
+	duckHost              string
+	duckToken             string
 	dynv6Host             string
 	dynv6Token            string
-}
+	configFile            string
 
-type BdcResponse struct {
-	IpString      string `json:"ipString"`
-	IpType        string `json:"ipType"`
-	IsBehindProxy bool   `json:"isBehindProxy"`
-}
+	influxHost   string
+	influxToken  string
+	influxOrg    string
+	influxBucket string
+	metricsAddr  string
 
-const (
-	host   = "http://localhost:8086"
-	token  = "" // TODO DO NOT COMMIT THIS!
-	org    = "influxtest"
-	bucket = "anserem"
-)
+	ipSources       []string
+	ipFamily        string
+	stunServers     []string
+	ipSourceTimeout time.Duration
+	ipCacheTTL      time.Duration
+
+	adminAddr        string
+	adminToken       string
+	adminTLSCertFile string
+	adminTLSKeyFile  string
+
+	collectHostMetrics  bool
+	hostMetricsInterval time.Duration
+
+	sinks      telemetry.Multi
+	resolver   *ipsource.Resolver
+	tracker    *adminapi.Tracker
+	dispatcher *providers.Dispatcher
+
+	// refreshRequests funnels POST /refresh onto the ticker loop so a
+	// remote-triggered refresh can never race the regular tick over the
+	// unsynchronized lastAddr/lastRefresh state below.
+	refreshRequests chan struct{}
+}
 
 var (
 	// refresh state
 	lastAddr    = ""
 	lastRefresh = time.Now()
-	// indexdb
-	client   influxdb2.Client
-	queryApi influxapi.QueryAPI
-	writeApi influxapi.WriteAPIBlocking
 )
 
 // Command is the entry point and defines the application itself.
@@ -92,10 +113,29 @@ func Command() *cli.Command {
 				Destination: &opts.forcedRefreshInterval,
 				Sources:     cli.EnvVars("FORCED_REFRESH_INTERVAL"),
 			},
+			&cli.StringFlag{
+				Name:        "duck-host",
+				Category:    "DUCK_DNS",
+				Required:    false,
+				Value:       "",
+				Usage:       "The DuckDNS host to refresh for",
+				Destination: &opts.duckHost,
+				Sources:     cli.EnvVars("DUCK_HOST"),
+			},
+			&cli.StringFlag{
+				Name:        "duck-token",
+				Category:    "DUCK_DNS",
+				Required:    false,
+				Value:       "",
+				Usage:       "The DuckDNS token for the provided host",
+				Destination: &opts.duckToken,
+				Sources:     cli.EnvVars("DUCK_TOKEN"),
+			},
 			&cli.StringFlag{
 				Name:        "dynv6-host",
 				Category:    "DYNV6",
-				Required:    true,
+				Required:    false,
+				Value:       "",
 				Usage:       "The dynv6 host to refresh for",
 				Destination: &opts.dynv6Host,
 				Sources:     cli.EnvVars("DYNV6_HOST"),
@@ -103,11 +143,164 @@ func Command() *cli.Command {
 			&cli.StringFlag{
 				Name:        "dynv6-token",
 				Category:    "DYNV6",
-				Required:    true,
+				Required:    false,
+				Value:       "",
 				Usage:       "The dynv6 token for the provided host",
 				Destination: &opts.dynv6Token,
 				Sources:     cli.EnvVars("DYNV6_TOKEN"),
 			},
+			&cli.StringFlag{
+				Name:        "config",
+				Category:    "GENERAL",
+				Required:    false,
+				Value:       "",
+				Usage:       "Path to a YAML or TOML file with additional provider definitions",
+				Destination: &opts.configFile,
+				Sources:     cli.EnvVars("CONFIG"),
+			},
+			&cli.StringFlag{
+				Name:        "influx-host",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       "",
+				Usage:       "InfluxDB host to write telemetry to; enables the InfluxDB sink when set",
+				Destination: &opts.influxHost,
+				Sources:     cli.EnvVars("INFLUX_HOST"),
+			},
+			&cli.StringFlag{
+				Name:        "influx-token",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       "",
+				Usage:       "InfluxDB API token",
+				Destination: &opts.influxToken,
+				Sources:     cli.EnvVars("INFLUX_TOKEN"),
+			},
+			&cli.StringFlag{
+				Name:        "influx-org",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       "",
+				Usage:       "InfluxDB organization",
+				Destination: &opts.influxOrg,
+				Sources:     cli.EnvVars("INFLUX_ORG"),
+			},
+			&cli.StringFlag{
+				Name:        "influx-bucket",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       "",
+				Usage:       "InfluxDB bucket",
+				Destination: &opts.influxBucket,
+				Sources:     cli.EnvVars("INFLUX_BUCKET"),
+			},
+			&cli.StringFlag{
+				Name:        "metrics-addr",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       "",
+				Usage:       "Bind address for the Prometheus /metrics endpoint; enables the Prometheus sink when set",
+				Destination: &opts.metricsAddr,
+				Sources:     cli.EnvVars("METRICS_ADDR"),
+			},
+			&cli.StringSliceFlag{
+				Name:        "ip-source",
+				Category:    "GENERAL",
+				Required:    false,
+				Value:       []string{"bdc"},
+				Usage:       "Public ip resolution strategy, tried in order; repeatable (iface, stun, bdc, ipify, ifconfig.co, opendns, google-dns)",
+				Destination: &opts.ipSources,
+				Sources:     cli.EnvVars("IP_SOURCE"),
+			},
+			&cli.StringFlag{
+				Name:        "ip-family",
+				Category:    "GENERAL",
+				Required:    false,
+				Value:       "v6",
+				Usage:       "The ip family to resolve: v4, v6, or dual",
+				Destination: &opts.ipFamily,
+				Sources:     cli.EnvVars("IP_FAMILY"),
+			},
+			&cli.StringSliceFlag{
+				Name:        "stun-server",
+				Category:    "GENERAL",
+				Required:    false,
+				Usage:       "STUN server(s) to query when ip-source includes \"stun\"; repeatable",
+				Destination: &opts.stunServers,
+				Sources:     cli.EnvVars("STUN_SERVER"),
+			},
+			&cli.DurationFlag{
+				Name:        "ip-source-timeout",
+				Category:    "GENERAL",
+				Required:    false,
+				Value:       5 * time.Second,
+				Usage:       "Per-source timeout while resolving the public ip",
+				Destination: &opts.ipSourceTimeout,
+				Sources:     cli.EnvVars("IP_SOURCE_TIMEOUT"),
+			},
+			&cli.DurationFlag{
+				Name:        "ip-cache-ttl",
+				Category:    "GENERAL",
+				Required:    false,
+				Value:       1 * time.Minute,
+				Usage:       "How long to keep preferring the last successful ip source",
+				Destination: &opts.ipCacheTTL,
+				Sources:     cli.EnvVars("IP_CACHE_TTL"),
+			},
+			&cli.StringFlag{
+				Name:        "admin-addr",
+				Category:    "ADMIN",
+				Required:    false,
+				Value:       "",
+				Usage:       "Bind address for the health/status/refresh admin API; disabled unless set",
+				Destination: &opts.adminAddr,
+				Sources:     cli.EnvVars("ADMIN_ADDR"),
+			},
+			&cli.StringFlag{
+				Name:        "admin-token",
+				Category:    "ADMIN",
+				Required:    false,
+				Value:       "",
+				Usage:       "Bearer token required to call the admin API; disabled unless set",
+				Destination: &opts.adminToken,
+				Sources:     cli.EnvVars("ADMIN_TOKEN"),
+			},
+			&cli.StringFlag{
+				Name:        "admin-tls-cert",
+				Category:    "ADMIN",
+				Required:    false,
+				Value:       "",
+				Usage:       "TLS certificate file for the admin API; requires admin-tls-key",
+				Destination: &opts.adminTLSCertFile,
+				Sources:     cli.EnvVars("ADMIN_TLS_CERT"),
+			},
+			&cli.StringFlag{
+				Name:        "admin-tls-key",
+				Category:    "ADMIN",
+				Required:    false,
+				Value:       "",
+				Usage:       "TLS key file for the admin API; requires admin-tls-cert",
+				Destination: &opts.adminTLSKeyFile,
+				Sources:     cli.EnvVars("ADMIN_TLS_KEY"),
+			},
+			&cli.BoolFlag{
+				Name:        "collect-host-metrics",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       false,
+				Usage:       "Collect host load, memory, and network metrics alongside DDNS ticks",
+				Destination: &opts.collectHostMetrics,
+				Sources:     cli.EnvVars("COLLECT_HOST_METRICS"),
+			},
+			&cli.DurationFlag{
+				Name:        "host-metrics-interval",
+				Category:    "TELEMETRY",
+				Required:    false,
+				Value:       1 * time.Minute,
+				Usage:       "The duration between host metrics collections",
+				Destination: &opts.hostMetricsInterval,
+				Sources:     cli.EnvVars("HOST_METRICS_INTERVAL"),
+			},
 		},
 		Action: opts.start,
 	}
@@ -116,57 +309,166 @@ func Command() *cli.Command {
 	return app
 }
 
+// providerConfigs builds the provider configs implied by the legacy
+// duck-host/duck-token and dynv6-host/dynv6-token flags, for users who have
+// not migrated to --config yet, and appends the entries read from --config.
+func (o *Options) providerConfigs() ([]providers.Config, error) {
+	var cfgs []providers.Config
+
+	if o.duckHost != "" || o.duckToken != "" {
+		cfgs = append(cfgs, providers.Config{
+			Type:     "duckdns",
+			Settings: map[string]string{"host": o.duckHost, "token": o.duckToken},
+		})
+	}
+	if o.dynv6Host != "" || o.dynv6Token != "" {
+		cfgs = append(cfgs, providers.Config{
+			Type:     "dynv6",
+			Settings: map[string]string{"host": o.dynv6Host, "token": o.dynv6Token},
+		})
+	}
+
+	fileCfgs, err := providers.LoadConfigFile(o.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(cfgs, fileCfgs...), nil
+}
+
 // start starts the application.
 func (o *Options) start(ctx *cli.Context) error {
 
-	// initialize influxdb client
-	client = influxdb2.NewClient(host, token)
-	writeApi = client.WriteAPIBlocking(org, bucket)
-	queryApi = client.QueryAPI(org)
+	cfgs, err := o.providerConfigs()
+	if err != nil {
+		return err
+	}
+	if len(cfgs) == 0 {
+		return fmt.Errorf("at least one provider has to be configured (via flags or --config)")
+	}
+
+	var enabled []providers.Provider
+	for _, cfg := range cfgs {
+		p, err := providers.New(cfg)
+		if err != nil {
+			return fmt.Errorf("configuring provider: %w", err)
+		}
+		enabled = append(enabled, p)
+	}
+	o.dispatcher, err = providers.NewDispatcher(enabled)
+	if err != nil {
+		return err
+	}
+
+	if o.influxHost != "" {
+		o.sinks = append(o.sinks, telemetry.NewInflux(telemetry.InfluxConfig{
+			Host:   o.influxHost,
+			Token:  o.influxToken,
+			Org:    o.influxOrg,
+			Bucket: o.influxBucket,
+		}))
+	}
+	if o.metricsAddr != "" {
+		o.sinks = append(o.sinks, telemetry.NewPrometheus(o.metricsAddr))
+	}
+	defer func() {
+		if err := o.sinks.Close(); err != nil {
+			log.Printf("error while closing telemetry sinks: %v", err)
+		}
+	}()
+
+	family, err := ipsource.ParseFamily(o.ipFamily)
+	if err != nil {
+		return err
+	}
+
+	var sources []ipsource.Source
+	for _, name := range o.ipSources {
+		src, err := ipsource.New(name, o.stunServers)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, src)
+	}
+	o.resolver = ipsource.NewResolver(sources, family, o.ipSourceTimeout, o.ipCacheTTL)
+
+	o.tracker = adminapi.NewTracker()
+	o.refreshRequests = make(chan struct{}, 1)
+	if o.adminAddr != "" {
+		if (o.adminTLSCertFile == "") != (o.adminTLSKeyFile == "") {
+			return fmt.Errorf("admin-tls-cert and admin-tls-key must both be set, or neither")
+		}
+		admin := adminapi.New(adminapi.Config{
+			Addr:                  o.adminAddr,
+			AuthToken:             o.adminToken,
+			TLSCertFile:           o.adminTLSCertFile,
+			TLSKeyFile:            o.adminTLSKeyFile,
+			ForcedRefreshInterval: o.forcedRefreshInterval,
+			Tracker:               o.tracker,
+			Refresh:               o.requestRefresh,
+		})
+		defer func() {
+			if err := admin.Close(); err != nil {
+				log.Printf("error while closing admin api: %v", err)
+			}
+		}()
+	}
 
 	// start ticker for periodic refreshes
 	ticker := time.NewTicker(o.refreshInterval)
 	defer ticker.Stop()
 
+	var hostMetricsTicker *time.Ticker
+	var hostMetricsC <-chan time.Time
+	if o.collectHostMetrics {
+		hostMetricsTicker = time.NewTicker(o.hostMetricsInterval)
+		defer hostMetricsTicker.Stop()
+		hostMetricsC = hostMetricsTicker.C
+	}
+
 	// keep refreshing
 	for {
 		select {
 		case <-ticker.C:
-			o.onTick()
+			o.onTick(ctx.Context)
+		case <-o.refreshRequests:
+			o.onTick(ctx.Context)
+		case <-hostMetricsC:
+			o.collectHostMetricsTick(ctx.Context)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
-func (o *Options) onTick() {
+// requestRefresh is called by the admin API's POST /refresh handler. It only
+// enqueues a tick on the main loop rather than running one itself, so
+// concurrent HTTP requests can never race the ticker over lastAddr/
+// lastRefresh; a request arriving while one is already queued is dropped, as
+// the queued tick will observe the same current state anyway.
+func (o *Options) requestRefresh(_ context.Context) {
+	select {
+	case o.refreshRequests <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Options) onTick(ctx context.Context) {
 
-	// get public address
-	addr, err := o.publicAddress()
+	// get public ip
+	ip, err := o.publicAddress(ctx)
 	if err != nil {
+		o.sinks.RecordProviderError("ipsource", anserr.CategoryOf(err))
 		log.Printf(err.Error())
 		return
 	}
+	addr := formatAddr(ip)
 
 	forced := time.Since(lastRefresh) > o.forcedRefreshInterval
 	changed := lastAddr != addr
 
-	// log tick
-	point := influxwrite.NewPoint(
-		"tick",
-		map[string]string{
-			"addr":      addr,
-			"last_addr": lastAddr,
-		},
-		map[string]interface{}{
-			"addr_changed": changed,
-			"forced":       forced,
-		},
-		time.Now(),
-	)
-	if err := writeApi.WritePoint(context.Background(), point); err != nil {
-		log.Printf("error while writing to indexdb: %v", err)
-	}
+	o.sinks.RecordTick(addr, changed, forced)
+	o.tracker.RecordRefresh(addr, time.Now())
 
 	// return if update is not necessary
 	if !forced && !changed {
@@ -174,89 +476,105 @@ func (o *Options) onTick() {
 	}
 
 	// refresh
-	o.refresh(addr)
+	o.refresh(ctx, ip)
 	lastRefresh = time.Now()
 	lastAddr = addr
 }
 
-func (o *Options) publicAddress() (string, error) {
+func (o *Options) publicAddress(ctx context.Context) (net.IP, error) {
 
-	// send request
 	start := time.Now()
-	res, err := http.Get("https://api-bdc.net/data/client-ip")
+	ip, err := o.resolver.Resolve(ctx)
 	took := time.Since(start)
+	o.sinks.RecordLookup("ipsource", took, err)
 	if err != nil {
-		return "", fmt.Errorf("error while getting public ip: %v", err)
+		return nil, fmt.Errorf("error while getting public ip: %w", err)
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	// get public address
-	var bdc BdcResponse
-	dec := json.NewDecoder(res.Body)
-	if err := dec.Decode(&bdc); err != nil {
-		return "", fmt.Errorf("error while decoding public ip response: %v", err)
-	}
+	return ip, nil
+}
 
-	// log time in influxdb
-	point := influxwrite.NewPoint(
-		"dbc-request-duration",
-		map[string]string{
-			"response_code": strconv.Itoa(res.StatusCode),
-		},
-		map[string]interface{}{
-			"ip":              bdc.IpString,
-			"ip_type":         bdc.IpType,
-			"is_behind_proxy": bdc.IsBehindProxy,
-			"duration":        took.Milliseconds(),
-		},
-		time.Now(),
-	)
-	if err := writeApi.WritePoint(context.Background(), point); err != nil {
-		log.Printf("error while writing to indexdb: %v", err)
+// formatAddr renders ip with the CIDR prefix length implied by its address
+// family, for use in logs and telemetry.
+func formatAddr(ip net.IP) string {
+	if ip.To4() != nil {
+		return fmt.Sprintf("%s/32", ip.String())
 	}
+	return fmt.Sprintf("%s/128", ip.String())
+}
+
+// refresh dispatches ip to every configured provider in parallel and records
+// the outcome of each; a provider whose breaker is open is skipped rather
+// than stalling the others.
+func (o *Options) refresh(ctx context.Context, ip net.IP) {
+	for _, result := range o.dispatcher.Update(ctx, ip) {
+		if result.Skipped {
+			log.Printf("%s: skipped, breaker open", result.Provider)
+			continue
+		}
 
-	return fmt.Sprintf("%s/128", bdc.IpString), nil
+		o.sinks.RecordProviderUpdate(result.Provider, result.Took, result.Err)
+		o.tracker.RecordProvider(result.Provider, result.Err)
+
+		if result.Err == nil {
+			log.Printf("%s: refreshed to %s", result.Provider, ip)
+			continue
+		}
+
+		o.sinks.RecordProviderError(result.Provider, result.Category)
+		log.Printf("%s: update failed (%s): %v", result.Provider, result.Category, result.Err)
+	}
 }
 
-func (o *Options) refresh(addr string) {
+// collectHostMetricsTick gathers host load, memory, and network counters via
+// gopsutil and writes them to the configured telemetry sinks. Individual
+// collectors are best-effort: a failure to read one is logged and does not
+// prevent the others from being reported.
+func (o *Options) collectHostMetricsTick(ctx context.Context) {
 
-	// send update request
-	start := time.Now()
-	res, err := http.Get(fmt.Sprintf(
-		"https://dynv6.com/api/update?hostname=%s&token=%s&ipv6=%s",
-		o.dynv6Host,
-		o.dynv6Token,
-		addr,
-	))
-	took := time.Since(start)
-	if err != nil {
-		log.Printf("error while updating ipv6 in Dynv6: %v", err)
-		return
+	if avg, err := load.AvgWithContext(ctx); err != nil {
+		log.Printf("error while reading load average: %v", err)
+	} else {
+		fields := map[string]interface{}{
+			"load1":  avg.Load1,
+			"load5":  avg.Load5,
+			"load15": avg.Load15,
+		}
+		if uptime, err := host.UptimeWithContext(ctx); err != nil {
+			log.Printf("error while reading uptime: %v", err)
+		} else {
+			fields["uptime_seconds"] = uptime
+		}
+		if count, err := cpu.CountsWithContext(ctx, true); err != nil {
+			log.Printf("error while reading cpu count: %v", err)
+		} else {
+			fields["cpu_count"] = count
+		}
+		o.sinks.RecordHostMetrics("host_load", fields)
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	str, err := io.ReadAll(res.Body)
-	if err != nil {
-		log.Printf("error while reading Dynv6 response: %v", err)
-		return
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err != nil {
+		log.Printf("error while reading memory stats: %v", err)
+	} else {
+		o.sinks.RecordHostMetrics("host_mem", map[string]interface{}{
+			"total":        vm.Total,
+			"used":         vm.Used,
+			"available":    vm.Available,
+			"used_percent": vm.UsedPercent,
+		})
 	}
-	log.Printf("refreshed DynV6: %s", str)
-
-	// log time in influxdb
-	point := influxwrite.NewPoint(
-		"dynv6-request-duration",
-		map[string]string{
-			"hostname":      o.dynv6Host,
-			"response_code": strconv.Itoa(res.StatusCode),
-		},
-		map[string]interface{}{
-			"duration": took.Milliseconds(),
-			"response": string(str),
-			"address":  addr,
-		},
-		time.Now(),
-	)
-	if err := writeApi.WritePoint(context.Background(), point); err != nil {
-		log.Printf("error while writing to indexdb: %v", err)
+
+	if counters, err := gopsutilnet.IOCountersWithContext(ctx, false); err != nil {
+		log.Printf("error while reading network counters: %v", err)
+	} else if len(counters) > 0 {
+		total := counters[0]
+		o.sinks.RecordHostMetrics("host_net", map[string]interface{}{
+			"bytes_sent":   total.BytesSent,
+			"bytes_recv":   total.BytesRecv,
+			"packets_sent": total.PacketsSent,
+			"packets_recv": total.PacketsRecv,
+			"errin":        total.Errin,
+			"errout":       total.Errout,
+		})
 	}
 }
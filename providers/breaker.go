@@ -0,0 +1,144 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"anserem/anserr"
+)
+
+// breakerState tracks the health of a single provider across ticks so a
+// persistently failing provider stops being retried every tick and instead
+// backs off exponentially.
+type breakerState struct {
+	mu          sync.Mutex
+	consecutive int
+	nextAttempt time.Time
+}
+
+const (
+	breakerBaseDelay = 5 * time.Second
+	breakerMaxDelay  = 10 * time.Minute
+	// breakerOpenAfter is the number of consecutive failures after which the
+	// breaker starts delaying attempts instead of retrying every tick.
+	breakerOpenAfter = 3
+)
+
+// allow reports whether a new attempt may be made right now.
+func (b *breakerState) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.consecutive < breakerOpenAfter || !now.Before(b.nextAttempt)
+}
+
+func (b *breakerState) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.consecutive = 0
+	b.nextAttempt = time.Time{}
+}
+
+// recordFailure opens or extends the breaker in response to err. A
+// RateLimited error's RetryAfter is honored verbatim instead of the usual
+// exponential backoff, and an AuthFailure holds the breaker open for
+// breakerMaxDelay since retrying with the same credentials cannot succeed.
+func (b *breakerState) recordFailure(now time.Time, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if d, ok := anserr.RetryAfterOf(err); ok {
+		b.consecutive = breakerOpenAfter
+		b.nextAttempt = now.Add(d)
+		return
+	}
+	if anserr.CategoryOf(err) == anserr.AuthFailure {
+		b.consecutive = breakerOpenAfter
+		b.nextAttempt = now.Add(breakerMaxDelay)
+		return
+	}
+
+	b.consecutive++
+	if b.consecutive < breakerOpenAfter {
+		return
+	}
+	delay := breakerBaseDelay << uint(b.consecutive-breakerOpenAfter)
+	if delay > breakerMaxDelay || delay <= 0 {
+		delay = breakerMaxDelay
+	}
+	// full jitter: spread retries across [0, delay) to avoid a thundering
+	// herd once several providers open their breakers at the same time.
+	delay = time.Duration(rand.Int63n(int64(delay)))
+	b.nextAttempt = now.Add(delay)
+}
+
+// Dispatcher fans updates out to a set of providers in parallel, tracking a
+// per-provider circuit breaker so one failing provider cannot stall or delay
+// the others.
+type Dispatcher struct {
+	providers []Provider
+	breakers  map[string]*breakerState
+}
+
+// NewDispatcher builds a Dispatcher for the given providers. It is an error
+// for two providers to share a Name(), since that name keys the breaker as
+// well as the Prometheus and Tracker labels reported for each provider; set
+// a distinct Config.ID to disambiguate multiple instances of the same type.
+func NewDispatcher(providers []Provider) (*Dispatcher, error) {
+	breakers := make(map[string]*breakerState, len(providers))
+	for _, p := range providers {
+		if _, exists := breakers[p.Name()]; exists {
+			return nil, fmt.Errorf("duplicate provider name %q; set a distinct id to disambiguate", p.Name())
+		}
+		breakers[p.Name()] = &breakerState{}
+	}
+	return &Dispatcher{providers: providers, breakers: breakers}, nil
+}
+
+// Result carries the outcome of a single provider update.
+type Result struct {
+	Provider string
+	Err      error
+	Category anserr.Category // classification of Err, Unknown if Err is nil or unclassified
+	Skipped  bool            // true if the breaker is open and the update was not attempted
+	Took     time.Duration   // wall time spent in Provider.Update; zero if Skipped
+}
+
+// Update runs Provider.Update for every provider concurrently, skipping
+// providers whose breaker is currently open, and returns one Result per
+// configured provider.
+func (d *Dispatcher) Update(ctx context.Context, ip net.IP) []Result {
+	results := make([]Result, len(d.providers))
+
+	var wg sync.WaitGroup
+	for i, p := range d.providers {
+		i, p := i, p
+		breaker := d.breakers[p.Name()]
+		now := time.Now()
+
+		if !breaker.allow(now) {
+			results[i] = Result{Provider: p.Name(), Skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			err := p.Update(ctx, ip)
+			took := time.Since(start)
+			if err != nil {
+				breaker.recordFailure(time.Now(), err)
+			} else {
+				breaker.recordSuccess()
+			}
+			results[i] = Result{Provider: p.Name(), Err: err, Category: anserr.CategoryOf(err), Took: took}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,110 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+func init() {
+	Register("cloudflare", newCloudflare)
+}
+
+// cloudflare updates an A/AAAA record through the Cloudflare v4 API using an
+// API token scoped to the zone's DNS edit permission.
+type cloudflare struct {
+	name     string
+	apiToken string
+	zoneID   string
+	recordID string
+	recordFQ string
+}
+
+func newCloudflare(cfg Config) (Provider, error) {
+	apiToken, err := require(cfg, "api_token")
+	if err != nil {
+		return nil, err
+	}
+	zoneID, err := require(cfg, "zone_id")
+	if err != nil {
+		return nil, err
+	}
+	recordID, err := require(cfg, "record_id")
+	if err != nil {
+		return nil, err
+	}
+	recordFQ, err := require(cfg, "record")
+	if err != nil {
+		return nil, err
+	}
+	return &cloudflare{
+		name:     instanceName("cloudflare", cfg),
+		apiToken: apiToken,
+		zoneID:   zoneID,
+		recordID: recordID,
+		recordFQ: recordFQ,
+	}, nil
+}
+
+func (c *cloudflare) Name() string { return c.name }
+
+type cloudflareUpdateRequest struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Proxied bool   `json:"proxied"`
+}
+
+type cloudflareResponse struct {
+	Success bool `json:"success"`
+	Errors  []struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (c *cloudflare) Update(ctx context.Context, ip net.IP) error {
+	recordType := "A"
+	if ip.To4() == nil {
+		recordType = "AAAA"
+	}
+
+	body, err := json.Marshal(cloudflareUpdateRequest{
+		Type:    recordType,
+		Name:    c.recordFQ,
+		Content: ip.String(),
+		TTL:     1, // automatic
+		Proxied: false,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+
+	url := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records/%s", c.zoneID, c.recordID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", c.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	var cfRes cloudflareResponse
+	if err := json.NewDecoder(res.Body).Decode(&cfRes); err != nil {
+		return fmt.Errorf("%s: decoding response: %w", c.name, err)
+	}
+	if !cfRes.Success {
+		return fmt.Errorf("%s: update rejected: %+v", c.name, cfRes.Errors)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileProviderConfig mirrors Config but with field names suited to a config
+// file (YAML/TOML) rather than flag parsing.
+type fileProviderConfig struct {
+	Type     string            `yaml:"type" toml:"type"`
+	ID       string            `yaml:"id" toml:"id"`
+	Settings map[string]string `yaml:"settings" toml:"settings"`
+}
+
+type fileConfig struct {
+	Providers []fileProviderConfig `yaml:"providers" toml:"providers"`
+}
+
+// LoadConfigFile reads additional provider definitions from a YAML or TOML
+// file, selected by its extension, so more than one instance of a given
+// provider type (e.g. two DuckDNS hosts) can be configured at once. An empty
+// path is not an error; it simply yields no configs.
+func LoadConfigFile(path string) ([]Config, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading provider config %s: %w", path, err)
+	}
+
+	var fc fileConfig
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &fc)
+	case ".toml":
+		err = toml.Unmarshal(data, &fc)
+	default:
+		return nil, fmt.Errorf("provider config %s: unsupported extension %q (want .yaml or .toml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing provider config %s: %w", path, err)
+	}
+
+	cfgs := make([]Config, 0, len(fc.Providers))
+	for _, p := range fc.Providers {
+		if p.Type == "" {
+			return nil, fmt.Errorf("provider config %s: entry missing type", path)
+		}
+		cfgs = append(cfgs, Config{Type: p.Type, ID: p.ID, Settings: p.Settings})
+	}
+
+	return cfgs, nil
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+func init() {
+	Register("digitalocean", newDigitalOcean)
+}
+
+// digitalOcean updates a domain record through the DigitalOcean v2 API.
+type digitalOcean struct {
+	name     string
+	apiToken string
+	domain   string
+	recordID string
+}
+
+func newDigitalOcean(cfg Config) (Provider, error) {
+	apiToken, err := require(cfg, "api_token")
+	if err != nil {
+		return nil, err
+	}
+	domain, err := require(cfg, "domain")
+	if err != nil {
+		return nil, err
+	}
+	recordID, err := require(cfg, "record_id")
+	if err != nil {
+		return nil, err
+	}
+	return &digitalOcean{
+		name:     instanceName("digitalocean", cfg),
+		apiToken: apiToken,
+		domain:   domain,
+		recordID: recordID,
+	}, nil
+}
+
+func (d *digitalOcean) Name() string { return d.name }
+
+type digitalOceanUpdateRequest struct {
+	Data string `json:"data"`
+}
+
+func (d *digitalOcean) Update(ctx context.Context, ip net.IP) error {
+	body, err := json.Marshal(digitalOceanUpdateRequest{Data: ip.String()})
+	if err != nil {
+		return fmt.Errorf("%s: %w", d.name, err)
+	}
+
+	url := fmt.Sprintf("https://api.digitalocean.com/v2/domains/%s/records/%s", d.domain, d.recordID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", d.name, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+d.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", d.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", d.name, res.Status)
+	}
+
+	return nil
+}
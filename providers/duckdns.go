@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"anserem/anserr"
+)
+
+func init() {
+	Register("duckdns", newDuckDNS)
+}
+
+type duckDNS struct {
+	name  string
+	host  string
+	token string
+}
+
+func newDuckDNS(cfg Config) (Provider, error) {
+	host, err := require(cfg, "host")
+	if err != nil {
+		return nil, err
+	}
+	token, err := require(cfg, "token")
+	if err != nil {
+		return nil, err
+	}
+	return &duckDNS{name: instanceName("duckdns", cfg), host: host, token: token}, nil
+}
+
+func (d *duckDNS) Name() string { return d.name }
+
+func (d *duckDNS) Update(ctx context.Context, ip net.IP) error {
+	if err := d.get(ctx, fmt.Sprintf(
+		"https://www.duckdns.org/update?domains=%s&token=%s&clear=true",
+		d.host, d.token,
+	)); err != nil {
+		return fmt.Errorf("%s: clear: %w", d.name, err)
+	}
+
+	if err := d.get(ctx, fmt.Sprintf(
+		"https://www.duckdns.org/update?domains=%s&token=%s&ip=&ipv6=%s",
+		d.host, d.token, ip.String(),
+	)); err != nil {
+		return fmt.Errorf("%s: update: %w", d.name, err)
+	}
+
+	return nil
+}
+
+func (d *duckDNS) get(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, err)
+	}
+
+	return classifyDuckDNSResponse(string(body))
+}
+
+// classifyDuckDNSResponse turns a DuckDNS update response body into a typed
+// error, or nil on success. DuckDNS reports success as "OK" and a rejected
+// token or domain as "KO"; any other body is treated as a protocol mismatch
+// worth surfacing but not worth retrying blindly.
+func classifyDuckDNSResponse(body string) error {
+	body = strings.TrimSpace(body)
+	switch body {
+	case "OK":
+		return nil
+	case "KO":
+		return anserr.New(anserr.AuthFailure, "update rejected: %s", body)
+	default:
+		return anserr.New(anserr.Permanent, "unexpected response: %s", body)
+	}
+}
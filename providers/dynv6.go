@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"anserem/anserr"
+)
+
+func init() {
+	Register("dynv6", newDynv6)
+}
+
+type dynv6 struct {
+	name  string
+	host  string
+	token string
+}
+
+func newDynv6(cfg Config) (Provider, error) {
+	host, err := require(cfg, "host")
+	if err != nil {
+		return nil, err
+	}
+	token, err := require(cfg, "token")
+	if err != nil {
+		return nil, err
+	}
+	return &dynv6{name: instanceName("dynv6", cfg), host: host, token: token}, nil
+}
+
+func (d *dynv6) Name() string { return d.name }
+
+func (d *dynv6) Update(ctx context.Context, ip net.IP) error {
+	url := fmt.Sprintf(
+		"https://dynv6.com/api/update?hostname=%s&token=%s&ipv6=%s",
+		d.host, d.token, ip.String(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, fmt.Errorf("%s: %w", d.name, err))
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, fmt.Errorf("%s: %w", d.name, err))
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return anserr.Wrap(anserr.Transient, fmt.Errorf("%s: reading response: %w", d.name, err))
+	}
+
+	return classifyDynv6Response(d.name, res.StatusCode, string(body))
+}
+
+// classifyDynv6Response turns a dynv6 update response into a typed error, or
+// nil on success. dynv6 reports HTTP 200 both when the address was changed
+// ("addresses updated: ...") and when it resends the address unchanged, so
+// any HTTP 200 is treated as success rather than matching one body; a
+// rejected token comes back as HTTP 401 with "authorization required". HTTP
+// 429 is classified as RateLimited with a one-hour RetryAfter, and any 5xx as
+// Transient. Any other response is treated as a protocol mismatch worth
+// surfacing but not worth retrying blindly.
+func classifyDynv6Response(name string, statusCode int, body string) error {
+	body = strings.TrimSpace(body)
+	switch {
+	case statusCode == http.StatusOK:
+		return nil
+	case statusCode == http.StatusUnauthorized:
+		return anserr.New(anserr.AuthFailure, "%s: update rejected: %s", name, body)
+	case statusCode == http.StatusTooManyRequests:
+		return anserr.New(anserr.RateLimited, "%s: rate limited: %s", name, body).RetryAfter(time.Hour)
+	case statusCode >= http.StatusInternalServerError:
+		return anserr.Wrap(anserr.Transient, fmt.Errorf("%s: server error (%d): %s", name, statusCode, body))
+	default:
+		return anserr.New(anserr.Permanent, "%s: unexpected response (%d): %s", name, statusCode, body)
+	}
+}
@@ -0,0 +1,85 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+func init() {
+	Register("gandi", newGandi)
+}
+
+// gandi updates an A/AAAA record through the Gandi LiveDNS v5 API.
+type gandi struct {
+	name   string
+	apiKey string
+	domain string
+	record string
+}
+
+func newGandi(cfg Config) (Provider, error) {
+	apiKey, err := require(cfg, "api_key")
+	if err != nil {
+		return nil, err
+	}
+	domain, err := require(cfg, "domain")
+	if err != nil {
+		return nil, err
+	}
+	record := optional(cfg, "record", "@")
+	return &gandi{
+		name:   instanceName("gandi", cfg),
+		apiKey: apiKey,
+		domain: domain,
+		record: record,
+	}, nil
+}
+
+func (g *gandi) Name() string { return g.name }
+
+type gandiUpdateRequest struct {
+	RRsetTTL    int      `json:"rrset_ttl"`
+	RRsetValues []string `json:"rrset_values"`
+}
+
+func (g *gandi) Update(ctx context.Context, ip net.IP) error {
+	recordType := "A"
+	if ip.To4() == nil {
+		recordType = "AAAA"
+	}
+
+	body, err := json.Marshal(gandiUpdateRequest{
+		RRsetTTL:    300,
+		RRsetValues: []string{ip.String()},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", g.name, err)
+	}
+
+	url := fmt.Sprintf(
+		"https://api.gandi.net/v5/livedns/domains/%s/records/%s/%s",
+		g.domain, g.record, recordType,
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", g.name, err)
+	}
+	req.Header.Set("Authorization", "Apikey "+g.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", g.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", g.name, res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+func init() {
+	Register("hetzner", newHetzner)
+}
+
+// hetzner updates a DNS record through the Hetzner DNS API.
+type hetzner struct {
+	name     string
+	apiToken string
+	zoneID   string
+	recordID string
+	record   string
+}
+
+func newHetzner(cfg Config) (Provider, error) {
+	apiToken, err := require(cfg, "api_token")
+	if err != nil {
+		return nil, err
+	}
+	zoneID, err := require(cfg, "zone_id")
+	if err != nil {
+		return nil, err
+	}
+	recordID, err := require(cfg, "record_id")
+	if err != nil {
+		return nil, err
+	}
+	record, err := require(cfg, "record")
+	if err != nil {
+		return nil, err
+	}
+	return &hetzner{
+		name:     instanceName("hetzner", cfg),
+		apiToken: apiToken,
+		zoneID:   zoneID,
+		recordID: recordID,
+		record:   record,
+	}, nil
+}
+
+func (h *hetzner) Name() string { return h.name }
+
+type hetznerUpdateRequest struct {
+	Value  string `json:"value"`
+	TTL    int    `json:"ttl"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	ZoneID string `json:"zone_id"`
+}
+
+func (h *hetzner) Update(ctx context.Context, ip net.IP) error {
+	recordType := "A"
+	if ip.To4() == nil {
+		recordType = "AAAA"
+	}
+
+	body, err := json.Marshal(hetznerUpdateRequest{
+		Value:  ip.String(),
+		TTL:    300,
+		Type:   recordType,
+		Name:   h.record,
+		ZoneID: h.zoneID,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", h.name, err)
+	}
+
+	url := fmt.Sprintf("https://dns.hetzner.com/api/v1/records/%s", h.recordID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", h.name, err)
+	}
+	req.Header.Set("Auth-API-Token", h.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", h.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %s", h.name, res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	Register("namecheap", newNamecheap)
+}
+
+// namecheap updates a host record through Namecheap's dynamic DNS update API.
+type namecheap struct {
+	name     string
+	host     string
+	domain   string
+	password string
+}
+
+func newNamecheap(cfg Config) (Provider, error) {
+	host, err := require(cfg, "host")
+	if err != nil {
+		return nil, err
+	}
+	domain, err := require(cfg, "domain")
+	if err != nil {
+		return nil, err
+	}
+	password, err := require(cfg, "password")
+	if err != nil {
+		return nil, err
+	}
+	return &namecheap{
+		name:     instanceName("namecheap", cfg),
+		host:     host,
+		domain:   domain,
+		password: password,
+	}, nil
+}
+
+func (n *namecheap) Name() string { return n.name }
+
+func (n *namecheap) Update(ctx context.Context, ip net.IP) error {
+	url := fmt.Sprintf(
+		"https://dynamicdns.park-your-domain.com/update?host=%s&domain=%s&password=%s&ip=%s",
+		n.host, n.domain, n.password, ip.String(),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", n.name, err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", n.name, err)
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("%s: reading response: %w", n.name, err)
+	}
+	if !strings.Contains(string(body), "<ErrCount>0</ErrCount>") {
+		return fmt.Errorf("%s: update rejected: %s", n.name, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,18 @@
+// Package providers defines the pluggable DDNS provider subsystem used by
+// anserem to push a detected public IP to one or more DNS hosts.
+package providers
+
+import (
+	"context"
+	"net"
+)
+
+// Provider updates a single DNS host to point at ip.
+type Provider interface {
+	// Name identifies the provider instance in logs and metrics, e.g.
+	// "duckdns:myhost" or "cloudflare:example.com".
+	Name() string
+
+	// Update pushes ip to the provider.
+	Update(ctx context.Context, ip net.IP) error
+}
@@ -0,0 +1,45 @@
+package providers
+
+import "fmt"
+
+// Config describes a single configured provider instance, whether it came
+// from a CLI flag or a config file entry.
+type Config struct {
+	// Type selects the registered factory, e.g. "duckdns" or "cloudflare".
+	Type string
+	// ID distinguishes multiple instances of the same Type, defaulting to
+	// Type if left empty.
+	ID string
+	// Settings holds the provider-specific options (host, token, zone, ...).
+	Settings map[string]string
+}
+
+// Factory builds a Provider from its Settings.
+type Factory func(cfg Config) (Provider, error)
+
+var registry = map[string]Factory{}
+
+// Register adds a Factory under typ so it can be instantiated via New. It is
+// meant to be called from provider implementations' init functions.
+func Register(typ string, f Factory) {
+	registry[typ] = f
+}
+
+// New instantiates the provider registered for cfg.Type.
+func New(cfg Config) (Provider, error) {
+	f, ok := registry[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider type %q", cfg.Type)
+	}
+	return f(cfg)
+}
+
+// Types returns the list of registered provider type names, mainly for
+// --help output and validation.
+func Types() []string {
+	types := make([]string, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	return types
+}
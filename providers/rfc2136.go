@@ -0,0 +1,106 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func init() {
+	Register("rfc2136", newRFC2136)
+}
+
+// rfc2136 updates a record via an RFC 2136 dynamic DNS update (nsupdate)
+// against an authoritative server, signed with a TSIG key when tsig_name is
+// set and sent unsigned otherwise.
+type rfc2136 struct {
+	name       string
+	server     string
+	zone       string
+	record     string
+	ttl        uint32
+	tsigName   string
+	tsigSecret string
+	tsigAlgo   string
+}
+
+func newRFC2136(cfg Config) (Provider, error) {
+	server, err := require(cfg, "server")
+	if err != nil {
+		return nil, err
+	}
+	zone, err := require(cfg, "zone")
+	if err != nil {
+		return nil, err
+	}
+	record, err := require(cfg, "record")
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := uint32(300)
+	if v, ok := cfg.Settings["ttl"]; ok {
+		parsed, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("rfc2136: invalid ttl %q: %w", v, err)
+		}
+		ttl = uint32(parsed)
+	}
+
+	return &rfc2136{
+		name:       instanceName("rfc2136", cfg),
+		server:     server,
+		zone:       dns.Fqdn(zone),
+		record:     dns.Fqdn(record),
+		ttl:        ttl,
+		tsigName:   optional(cfg, "tsig_name", ""),
+		tsigSecret: optional(cfg, "tsig_secret", ""),
+		tsigAlgo:   optional(cfg, "tsig_algorithm", dns.HmacSHA256),
+	}, nil
+}
+
+func (r *rfc2136) Name() string { return r.name }
+
+func (r *rfc2136) Update(ctx context.Context, ip net.IP) error {
+	rrType := dns.TypeA
+	if ip.To4() == nil {
+		rrType = dns.TypeAAAA
+	}
+
+	msg := new(dns.Msg)
+	msg.SetUpdate(r.zone)
+
+	remove, err := dns.NewRR(fmt.Sprintf("%s 0 %s", r.record, dns.TypeToString[rrType]))
+	if err != nil {
+		return fmt.Errorf("%s: building removal record: %w", r.name, err)
+	}
+	insert, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", r.record, r.ttl, dns.TypeToString[rrType], ip.String()))
+	if err != nil {
+		return fmt.Errorf("%s: building record: %w", r.name, err)
+	}
+
+	msg.RemoveRRset([]dns.RR{remove})
+	msg.Insert([]dns.RR{insert})
+
+	client := new(dns.Client)
+	client.Net = "udp"
+
+	if r.tsigName != "" {
+		msg.SetTsig(dns.Fqdn(r.tsigName), r.tsigAlgo, 300, time.Now().Unix())
+		client.TsigSecret = map[string]string{dns.Fqdn(r.tsigName): r.tsigSecret}
+	}
+
+	reply, _, err := client.ExchangeContext(ctx, msg, r.server)
+	if err != nil {
+		return fmt.Errorf("%s: %w", r.name, err)
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("%s: nsupdate rejected: %s", r.name, dns.RcodeToString[reply.Rcode])
+	}
+
+	return nil
+}
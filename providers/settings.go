@@ -0,0 +1,28 @@
+package providers
+
+import "fmt"
+
+// require fetches a mandatory setting from cfg, returning an error naming
+// both the provider type and the missing key.
+func require(cfg Config, key string) (string, error) {
+	v, ok := cfg.Settings[key]
+	if !ok || v == "" {
+		return "", fmt.Errorf("%s: missing required setting %q", cfg.Type, key)
+	}
+	return v, nil
+}
+
+// optional fetches a setting from cfg, falling back to def if unset.
+func optional(cfg Config, key, def string) string {
+	if v, ok := cfg.Settings[key]; ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func instanceName(typ string, cfg Config) string {
+	if cfg.ID != "" {
+		return fmt.Sprintf("%s:%s", typ, cfg.ID)
+	}
+	return typ
+}
@@ -0,0 +1,101 @@
+package telemetry
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	influxapi "github.com/influxdata/influxdb-client-go/v2/api"
+	influxwrite "github.com/influxdata/influxdb-client-go/v2/api/write"
+
+	"anserem/anserr"
+)
+
+// InfluxConfig holds the connection details for an InfluxDB v2 sink.
+type InfluxConfig struct {
+	Host   string
+	Token  string
+	Org    string
+	Bucket string
+}
+
+// influxSink writes tick, lookup, and provider-update points to InfluxDB.
+// The "tick" measurement carries an addr tag and addr_changed/forced fields,
+// with no last_addr tag. Lookups and provider updates are written under the
+// generic "lookup-duration" and "provider-update-duration" measurements,
+// tagged by source or provider and success, not under per-service
+// measurements such as "dbc-request-duration" or "dynv6-request-duration".
+type influxSink struct {
+	client   influxdb2.Client
+	writeApi influxapi.WriteAPIBlocking
+}
+
+// NewInflux builds a Sink backed by an InfluxDB v2 instance.
+func NewInflux(cfg InfluxConfig) Sink {
+	client := influxdb2.NewClient(cfg.Host, cfg.Token)
+	return &influxSink{
+		client:   client,
+		writeApi: client.WriteAPIBlocking(cfg.Org, cfg.Bucket),
+	}
+}
+
+func (s *influxSink) write(point *influxwrite.Point) {
+	if err := s.writeApi.WritePoint(context.Background(), point); err != nil {
+		log.Printf("telemetry: error while writing to influxdb: %v", err)
+	}
+}
+
+func (s *influxSink) RecordTick(addr string, changed, forced bool) {
+	s.write(influxwrite.NewPoint(
+		"tick",
+		map[string]string{"addr": addr},
+		map[string]interface{}{"addr_changed": changed, "forced": forced},
+		time.Now(),
+	))
+}
+
+func (s *influxSink) RecordLookup(source string, took time.Duration, err error) {
+	fields := map[string]interface{}{"duration": took.Milliseconds()}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.write(influxwrite.NewPoint(
+		"lookup-duration",
+		map[string]string{"source": source, "success": strconv.FormatBool(err == nil)},
+		fields,
+		time.Now(),
+	))
+}
+
+func (s *influxSink) RecordProviderUpdate(provider string, took time.Duration, err error) {
+	fields := map[string]interface{}{"duration": took.Milliseconds()}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	s.write(influxwrite.NewPoint(
+		"provider-update-duration",
+		map[string]string{"provider": provider, "success": strconv.FormatBool(err == nil)},
+		fields,
+		time.Now(),
+	))
+}
+
+func (s *influxSink) RecordProviderError(provider string, category anserr.Category) {
+	s.write(influxwrite.NewPoint(
+		"provider-error",
+		map[string]string{"provider": provider, "category": category.String()},
+		map[string]interface{}{"count": 1},
+		time.Now(),
+	))
+}
+
+func (s *influxSink) RecordHostMetrics(measurement string, fields map[string]interface{}) {
+	s.write(influxwrite.NewPoint(measurement, nil, fields, time.Now()))
+}
+
+func (s *influxSink) Close() error {
+	s.client.Close()
+	return nil
+}
@@ -0,0 +1,153 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"anserem/anserr"
+)
+
+// prometheusSink exposes anserem's refresh-loop metrics on an HTTP /metrics
+// endpoint for scraping.
+type prometheusSink struct {
+	registry *prometheus.Registry
+	server   *http.Server
+
+	ipChanges      prometheus.Counter
+	updateDuration *prometheus.HistogramVec
+	lastRefresh    prometheus.Gauge
+	providerUp     *prometheus.GaugeVec
+	providerErrors *prometheus.CounterVec
+
+	hostGaugesMu sync.Mutex
+	hostGauges   map[string]prometheus.Gauge
+}
+
+// NewPrometheus builds a Sink that serves Prometheus metrics on addr and
+// starts listening immediately in the background.
+func NewPrometheus(addr string) Sink {
+	registry := prometheus.NewRegistry()
+
+	s := &prometheusSink{
+		registry:   registry,
+		hostGauges: make(map[string]prometheus.Gauge),
+		ipChanges: promauto.With(registry).NewCounter(prometheus.CounterOpts{
+			Name: "anserem_public_ip_changes_total",
+			Help: "Number of times the detected public IP changed between ticks.",
+		}),
+		updateDuration: promauto.With(registry).NewHistogramVec(prometheus.HistogramOpts{
+			Name: "anserem_provider_update_duration_seconds",
+			Help: "Duration of DDNS provider update calls.",
+		}, []string{"provider", "result"}),
+		lastRefresh: promauto.With(registry).NewGauge(prometheus.GaugeOpts{
+			Name: "anserem_last_refresh_timestamp_seconds",
+			Help: "Unix timestamp of the last refresh tick.",
+		}),
+		providerUp: promauto.With(registry).NewGaugeVec(prometheus.GaugeOpts{
+			Name: "anserem_provider_up",
+			Help: "Whether the last update to a provider succeeded (1) or not (0).",
+		}, []string{"provider"}),
+		providerErrors: promauto.With(registry).NewCounterVec(prometheus.CounterOpts{
+			Name: "anserem_provider_errors_total",
+			Help: "Classified provider update failures, by provider and category.",
+		}, []string{"provider", "category"}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	s.server = &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("telemetry: prometheus server stopped: %v", err)
+		}
+	}()
+
+	return s
+}
+
+func (s *prometheusSink) RecordTick(addr string, changed, forced bool) {
+	if changed {
+		s.ipChanges.Inc()
+	}
+	s.lastRefresh.SetToCurrentTime()
+}
+
+func (s *prometheusSink) RecordLookup(source string, took time.Duration, err error) {
+	// lookups are not a configured DDNS provider, so they are left out of
+	// anserem_provider_update_duration_seconds to avoid conflating the two.
+}
+
+func (s *prometheusSink) RecordProviderUpdate(provider string, took time.Duration, err error) {
+	result := "success"
+	up := 1.0
+	if err != nil {
+		result = "error"
+		up = 0.0
+	}
+	s.updateDuration.WithLabelValues(provider, result).Observe(took.Seconds())
+	s.providerUp.WithLabelValues(provider).Set(up)
+}
+
+func (s *prometheusSink) RecordProviderError(provider string, category anserr.Category) {
+	s.providerErrors.WithLabelValues(provider, category.String()).Inc()
+}
+
+func (s *prometheusSink) RecordHostMetrics(measurement string, fields map[string]interface{}) {
+	for field, value := range fields {
+		v, ok := toFloat64(value)
+		if !ok {
+			continue
+		}
+		s.hostGauge(measurement, field).Set(v)
+	}
+}
+
+func (s *prometheusSink) hostGauge(measurement, field string) prometheus.Gauge {
+	name := fmt.Sprintf("anserem_%s_%s", measurement, field)
+
+	s.hostGaugesMu.Lock()
+	defer s.hostGaugesMu.Unlock()
+
+	if g, ok := s.hostGauges[name]; ok {
+		return g
+	}
+	g := promauto.With(s.registry).NewGauge(prometheus.GaugeOpts{
+		Name: name,
+		Help: fmt.Sprintf("Host metric %s reported under measurement %s.", field, measurement),
+	})
+	s.hostGauges[name] = g
+	return g
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func (s *prometheusSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,82 @@
+// Package telemetry decouples anserem's refresh loop from any one metrics
+// backend, so operators can enable InfluxDB, Prometheus, both, or neither.
+package telemetry
+
+import (
+	"time"
+
+	"anserem/anserr"
+)
+
+// Sink receives the telemetry events emitted by a refresh tick. Methods must
+// be safe to call concurrently and must not block the refresh loop for long.
+type Sink interface {
+	// RecordTick reports the outcome of a single tick: the detected address,
+	// whether it differs from the previously known one, and whether the
+	// refresh was forced due to forcedRefreshInterval elapsing.
+	RecordTick(addr string, changed, forced bool)
+
+	// RecordLookup reports how long a public IP lookup against source took,
+	// and whether it succeeded.
+	RecordLookup(source string, took time.Duration, err error)
+
+	// RecordProviderUpdate reports how long a DDNS provider update took, and
+	// whether it succeeded.
+	RecordProviderUpdate(provider string, took time.Duration, err error)
+
+	// RecordProviderError reports a classified provider failure, so
+	// operators can alert on auth failures separately from transient
+	// network issues instead of tailing logs.
+	RecordProviderError(provider string, category anserr.Category)
+
+	// RecordHostMetrics reports a batch of host metrics collected under a
+	// single measurement, e.g. "host_load", "host_mem", or "host_net".
+	RecordHostMetrics(measurement string, fields map[string]interface{})
+
+	// Close releases any resources held by the sink.
+	Close() error
+}
+
+// Multi fans a single set of events out to every configured Sink, so callers
+// can treat zero, one, or several enabled sinks uniformly.
+type Multi []Sink
+
+func (m Multi) RecordTick(addr string, changed, forced bool) {
+	for _, s := range m {
+		s.RecordTick(addr, changed, forced)
+	}
+}
+
+func (m Multi) RecordLookup(source string, took time.Duration, err error) {
+	for _, s := range m {
+		s.RecordLookup(source, took, err)
+	}
+}
+
+func (m Multi) RecordProviderUpdate(provider string, took time.Duration, err error) {
+	for _, s := range m {
+		s.RecordProviderUpdate(provider, took, err)
+	}
+}
+
+func (m Multi) RecordProviderError(provider string, category anserr.Category) {
+	for _, s := range m {
+		s.RecordProviderError(provider, category)
+	}
+}
+
+func (m Multi) RecordHostMetrics(measurement string, fields map[string]interface{}) {
+	for _, s := range m {
+		s.RecordHostMetrics(measurement, fields)
+	}
+}
+
+func (m Multi) Close() error {
+	var firstErr error
+	for _, s := range m {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}